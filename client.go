@@ -5,9 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httputil"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -16,6 +20,10 @@ const (
 
 	apiFormatVersionHeader = "X-GOOG-API-FORMAT-VERSION"
 	apiFormatVersion       = "2"
+
+	// defaultMaxWorkers bounds how many of the messages passed to SendEach
+	// and friends are in flight to the FCM server at the same time.
+	defaultMaxWorkers = 50
 )
 
 // Client abstracts the interaction between the application server and the
@@ -27,27 +35,66 @@ type Client struct {
 	projectID     string
 	fcmEndpoint   string
 	batchEndpoint string
+	iidEndpoint   string
 
 	// the endpoint for the project
-	sendEndpoint  string
+	sendEndpoint string
 
 	client        *http.Client
-	tokenProvider *tokenProvider
+	tokenProvider TokenProvider
+
+	// ownsTransport reports whether c.client.Transport is a *http.Transport
+	// this package cloned for its own exclusive use, as opposed to one a
+	// caller supplied via WithHTTPClient. It lets transport() mutate tuning
+	// in place on later calls without re-cloning, while never mutating a
+	// transport it doesn't own.
+	ownsTransport bool
+
+	// maxWorkers bounds the concurrency of the per-message send path used by
+	// SendEach and friends.
+	maxWorkers int
+
+	// retryPolicy, when set via WithRetry, causes transient send failures to
+	// be retried with exponential backoff instead of returned immediately.
+	retryPolicy *RetryPolicy
+
+	// requestTimeout, when set via WithTransportDefaults or
+	// WithRequestTimeout, bounds how long a single outgoing HTTP request may
+	// take.
+	requestTimeout time.Duration
+
+	// metrics, when set via WithMetrics, receives Prometheus observations
+	// for each send attempt.
+	metrics *clientMetrics
+	// tracer, when set via WithTracer, is used to start a span around each
+	// send call and HTTP round-trip.
+	tracer trace.Tracer
 }
 
 // NewClient creates new Firebase Cloud Messaging Client based on a json service account file credentials file.
 func NewClient(projectID string, credentialsLocation string, opts ...Option) (*Client, error) {
-	tp, err := newTokenProvider(credentialsLocation)
+	tp, err := ServiceAccountFileTokenProvider(credentialsLocation)
 	if err != nil {
 		return nil, err
 	}
 
+	return NewClientWithTokenProvider(projectID, tp, opts...)
+}
+
+// NewClientWithTokenProvider creates a new Firebase Cloud Messaging Client
+// that authenticates using the given TokenProvider, instead of the service
+// account credentials file NewClient expects. This is the entry point for
+// running the client on Google Cloud via WorkloadIdentityTokenProvider, or
+// against a StaticTokenProvider in tests.
+func NewClientWithTokenProvider(projectID string, tp TokenProvider, opts ...Option) (*Client, error) {
 	c := &Client{
 		projectID:     projectID,
 		fcmEndpoint:   defaultEndpoint,
 		batchEndpoint: defaultBatchEndpoint,
+		iidEndpoint:   defaultIIDEndpoint,
 		client:        http.DefaultClient,
 		tokenProvider: tp,
+		maxWorkers:    defaultMaxWorkers,
 	}
 	c.sendEndpoint = fmt.Sprintf("%s/projects/%s/messages:send", c.fcmEndpoint, c.projectID)
 
@@ -73,11 +120,41 @@ func (c *Client) Send(ctx context.Context, req *SendRequest) (string, error) {
 		return "", err
 	}
 
-	return c.send(ctx, data)
+	return c.send(ctx, data, req.ValidateOnly)
 }
 
-// send sends a request.
-func (c *Client) send(ctx context.Context, data []byte) (messageID string, err error) {
+// send sends a request, retrying transient failures according to the
+// client's RetryPolicy, if any, and reporting the outcome via the
+// client's metrics and tracer, if configured.
+func (c *Client) send(ctx context.Context, data []byte, dryRun bool) (string, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "fcm.send", dryRun, 1)
+
+	var messageID string
+	err := c.withRetry(ctx, func() error {
+		id, err := c.sendOnce(ctx, data)
+		if err != nil {
+			return err
+		}
+		messageID = id
+		return nil
+	})
+
+	endSpan(span, err)
+	c.metrics.observeSend(start, err)
+	return messageID, err
+}
+
+// sendOnce sends a single request, without retrying. It is called once per
+// attempt, so each retry gets its own round-trip span as a child of the
+// Send/SendEach span created by send().
+func (c *Client) sendOnce(ctx context.Context, data []byte) (messageID string, err error) {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	ctx, span := c.startRoundTripSpan(ctx, "fcm.http.round_trip")
+	defer func() { endSpan(span, err) }()
+
 	// create request
 	req, err := http.NewRequestWithContext(ctx, "POST", c.sendEndpoint, bytes.NewBuffer(data))
 	if err != nil {
@@ -85,7 +162,7 @@ func (c *Client) send(ctx context.Context, data []byte) (messageID string, err e
 	}
 
 	// get bearer token
-	token, err := c.tokenProvider.token()
+	token, _, err := c.tokenProvider.Token(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -103,17 +180,30 @@ func (c *Client) send(ctx context.Context, data []byte) (messageID string, err e
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		retryAfter := retryAfterFromResponse(resp.Header)
+
+		if fe := parseFCMError(resp.StatusCode, bodyBytes); fe != nil {
+			fe.RetryAfter = retryAfter
+			return "", fe
+		}
+
 		requestBytes, _ := httputil.DumpRequest(req, true)
 		responseBytes, _ := httputil.DumpResponse(resp, true)
 
 		if resp.StatusCode >= http.StatusInternalServerError {
 			return "", HttpError{
+				StatusCode:   resp.StatusCode,
+				RetryAfter:   retryAfter,
 				RequestDump:  string(requestBytes),
 				ResponseDump: string(responseBytes),
 				Err:          fmt.Errorf(fmt.Sprintf("%d error: %s", resp.StatusCode, resp.Status)),
 			}
 		}
 		return "", HttpError{
+			StatusCode:   resp.StatusCode,
+			RetryAfter:   retryAfter,
 			RequestDump:  string(requestBytes),
 			ResponseDump: string(responseBytes),
 			Err:          fmt.Errorf("%d error: %s", resp.StatusCode, resp.Status),
@@ -140,6 +230,10 @@ func (c *Client) send(ctx context.Context, data []byte) (messageID string, err e
 
 // HttpError contains the dump of the request and response for debugging purposes.
 type HttpError struct {
+	StatusCode int
+	// RetryAfter is the delay requested by the server's Retry-After header,
+	// if any.
+	RetryAfter   time.Duration
 	RequestDump  string
 	ResponseDump string
 	Err          error
@@ -0,0 +1,103 @@
+package fcm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// fcmErrorDetailType is the `@type` of the error detail entry that carries
+// the FCM-specific error code in a google.rpc.Status error body.
+const fcmErrorDetailType = "type.googleapis.com/google.firebase.fcm.v1.FcmError"
+
+// FCMErrorCode identifies the well-known error codes the FCM v1 API reports
+// in the `error.details[].errorCode` field of its google.rpc.Status error
+// responses.
+//
+// See https://firebase.google.com/docs/reference/fcm/rest/v1/ErrorCode
+type FCMErrorCode string
+
+const (
+	ErrorCodeUnspecified         FCMErrorCode = "UNSPECIFIED_ERROR"
+	ErrorCodeInvalidArgument     FCMErrorCode = "INVALID_ARGUMENT"
+	ErrorCodeUnregistered        FCMErrorCode = "UNREGISTERED"
+	ErrorCodeSenderIDMismatch    FCMErrorCode = "SENDER_ID_MISMATCH"
+	ErrorCodeQuotaExceeded       FCMErrorCode = "QUOTA_EXCEEDED"
+	ErrorCodeUnavailable         FCMErrorCode = "UNAVAILABLE"
+	ErrorCodeInternal            FCMErrorCode = "INTERNAL"
+	ErrorCodeThirdPartyAuthError FCMErrorCode = "THIRD_PARTY_AUTH_ERROR"
+)
+
+// FCMError represents a structured error reported by the FCM v1 API, as
+// opposed to a transport-level failure that never reached the API handler.
+// It is returned by Client.send and folded into SendResponse by the batch
+// and per-message send paths.
+type FCMError struct {
+	HTTPStatus int
+	Code       FCMErrorCode
+	Message    string
+	// RetryAfter is the delay requested by the server's Retry-After header,
+	// if any.
+	RetryAfter time.Duration
+}
+
+func (e *FCMError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%d %s: %s", e.HTTPStatus, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%d error: %s", e.HTTPStatus, e.Message)
+}
+
+// IsRegistrationTokenInvalid reports whether the error indicates that the
+// target registration token is no longer valid and should be removed from
+// storage. ErrorCodeInvalidArgument is deliberately excluded: it covers
+// malformed messages (bad topic name, oversized payload, bad TTL, ...), not
+// just dead tokens, and callers prune on this signal.
+func (e *FCMError) IsRegistrationTokenInvalid() bool {
+	return e.Code == ErrorCodeUnregistered
+}
+
+// IsQuotaExceeded reports whether the send failed because a rate limiting
+// quota was exceeded.
+func (e *FCMError) IsQuotaExceeded() bool {
+	return e.Code == ErrorCodeQuotaExceeded
+}
+
+// IsRetryable reports whether the failure is transient, i.e. retrying the
+// request later is likely to succeed rather than fail for the same reason.
+func (e *FCMError) IsRetryable() bool {
+	switch e.Code {
+	case ErrorCodeUnavailable, ErrorCodeInternal:
+		return true
+	}
+	return e.HTTPStatus == http.StatusTooManyRequests || e.HTTPStatus >= http.StatusInternalServerError
+}
+
+// parseFCMError extracts a structured FCMError from a non-200 FCM v1 API
+// response body. It returns nil if the body does not look like a
+// google.rpc.Status error response, so that callers can fall back to the
+// HttpError dump path instead of reporting a blank error message for
+// bodies from e.g. an intermediate proxy.
+func parseFCMError(httpStatus int, body []byte) *FCMError {
+	var errResp fcmErrorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return nil
+	}
+	if errResp.Error.Message == "" && len(errResp.Error.Details) == 0 {
+		return nil
+	}
+
+	fe := &FCMError{
+		HTTPStatus: httpStatus,
+		Message:    errResp.Error.Message,
+	}
+	for _, d := range errResp.Error.Details {
+		if d.Type == fcmErrorDetailType && d.ErrorCode != "" {
+			fe.Code = FCMErrorCode(d.ErrorCode)
+			break
+		}
+	}
+
+	return fe
+}
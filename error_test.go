@@ -0,0 +1,93 @@
+package fcm
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseFCMError(t *testing.T) {
+	tests := []struct {
+		name       string
+		httpStatus int
+		body       string
+		wantNil    bool
+		wantCode   FCMErrorCode
+		wantMsg    string
+	}{
+		{
+			name:       "valid error with code",
+			httpStatus: http.StatusNotFound,
+			body: `{
+				"error": {
+					"message": "Requested entity was not found.",
+					"details": [
+						{
+							"@type": "type.googleapis.com/google.firebase.fcm.v1.FcmError",
+							"errorCode": "UNREGISTERED"
+						}
+					]
+				}
+			}`,
+			wantCode: ErrorCodeUnregistered,
+			wantMsg:  "Requested entity was not found.",
+		},
+		{
+			name:       "valid error without FCM detail",
+			httpStatus: http.StatusBadRequest,
+			body:       `{"error": {"message": "Invalid request"}}`,
+			wantMsg:    "Invalid request",
+		},
+		{
+			name:       "non-FCM JSON body",
+			httpStatus: http.StatusBadGateway,
+			body:       `{"message": "upstream connect error"}`,
+			wantNil:    true,
+		},
+		{
+			name:       "empty object",
+			httpStatus: http.StatusInternalServerError,
+			body:       `{}`,
+			wantNil:    true,
+		},
+		{
+			name:       "invalid JSON",
+			httpStatus: http.StatusInternalServerError,
+			body:       `not json`,
+			wantNil:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fe := parseFCMError(tt.httpStatus, []byte(tt.body))
+			if tt.wantNil {
+				if fe != nil {
+					t.Fatalf("parseFCMError() = %+v, want nil", fe)
+				}
+				return
+			}
+
+			if fe == nil {
+				t.Fatal("parseFCMError() = nil, want non-nil")
+			}
+			if fe.HTTPStatus != tt.httpStatus {
+				t.Errorf("HTTPStatus = %d, want %d", fe.HTTPStatus, tt.httpStatus)
+			}
+			if fe.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", fe.Code, tt.wantCode)
+			}
+			if fe.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", fe.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestIsRegistrationTokenInvalid(t *testing.T) {
+	if (&FCMError{Code: ErrorCodeInvalidArgument}).IsRegistrationTokenInvalid() {
+		t.Error("INVALID_ARGUMENT must not be treated as a dead-token signal")
+	}
+	if !(&FCMError{Code: ErrorCodeUnregistered}).IsRegistrationTokenInvalid() {
+		t.Error("UNREGISTERED must be treated as a dead-token signal")
+	}
+}
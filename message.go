@@ -0,0 +1,89 @@
+package fcm
+
+import "errors"
+
+// SendRequest represents the body of a `projects.messages.send` request.
+type SendRequest struct {
+	ValidateOnly bool     `json:"validate_only,omitempty"`
+	Message      *Message `json:"message,omitempty"`
+}
+
+// SendResponse represents the outcome of sending a single message. It is
+// used by the batch (`SendAll`) and per-message (`SendEach`) send paths to
+// report per-message results alongside a `MulticastResponse`.
+type SendResponse struct {
+	Success   bool
+	MessageID string
+	Error     *FCMError
+}
+
+// Message represents a message that can be sent to a single device,
+// condition or topic via the FCM v1 API.
+//
+// See https://firebase.google.com/docs/reference/fcm/rest/v1/projects.messages
+type Message struct {
+	// Exactly one of Token, Topic or Condition must be set.
+	Token     string `json:"token,omitempty"`
+	Topic     string `json:"topic,omitempty"`
+	Condition string `json:"condition,omitempty"`
+
+	Data         map[string]string `json:"data,omitempty"`
+	Notification *Notification     `json:"notification,omitempty"`
+	Android      *AndroidConfig    `json:"android,omitempty"`
+	Webpush      *WebpushConfig    `json:"webpush,omitempty"`
+	Apns         *APNSConfig       `json:"apns,omitempty"`
+}
+
+// Notification represents the basic notification template to use across all
+// platforms.
+type Notification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+	Image string `json:"image,omitempty"`
+}
+
+// AndroidConfig represents the Android-specific options that can be included
+// in a Message.
+type AndroidConfig struct {
+	CollapseKey  string            `json:"collapse_key,omitempty"`
+	Priority     string            `json:"priority,omitempty"`
+	TTL          string            `json:"ttl,omitempty"`
+	Data         map[string]string `json:"data,omitempty"`
+	Notification *Notification     `json:"notification,omitempty"`
+}
+
+// WebpushConfig represents the Webpush-specific options that can be included
+// in a Message.
+type WebpushConfig struct {
+	Headers      map[string]string `json:"headers,omitempty"`
+	Data         map[string]string `json:"data,omitempty"`
+	Notification *Notification     `json:"notification,omitempty"`
+}
+
+// APNSConfig represents the APNs-specific options that can be included in a
+// Message.
+type APNSConfig struct {
+	Headers map[string]string      `json:"headers,omitempty"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// Validate checks that the Message satisfies the constraints the FCM API
+// enforces client-side, so that obviously malformed messages fail fast
+// instead of round-tripping to the server.
+func (m *Message) Validate() error {
+	if m == nil {
+		return errors.New("message must not be nil")
+	}
+
+	targets := 0
+	for _, t := range []string{m.Token, m.Topic, m.Condition} {
+		if t != "" {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return errors.New("message must have exactly one of token, topic or condition set")
+	}
+
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/textproto"
+	"time"
 )
 
 const (
@@ -134,7 +135,7 @@ func toMessages(message *MulticastMessage) ([]*Message, error) {
 	return message.toMessages()
 }
 
-func (c *Client) sendBatch(ctx context.Context, messages []*Message, dryRun bool) (*MulticastResponse, error) {
+func (c *Client) sendBatch(ctx context.Context, messages []*Message, dryRun bool) (response *MulticastResponse, err error) {
 	if len(messages) == 0 {
 		return nil, errors.New("messages must not be nil or empty")
 	}
@@ -143,7 +144,17 @@ func (c *Client) sendBatch(ctx context.Context, messages []*Message, dryRun bool
 		return nil, fmt.Errorf("messages must not contain more than %d elements", maxMessages)
 	}
 
-	request, err := c.newBatchRequest(messages, dryRun)
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "fcm.SendAll", dryRun, len(messages))
+	defer func() {
+		endSpan(span, err)
+		c.metrics.observeSend(start, err)
+	}()
+
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	request, err := c.newBatchRequest(ctx, messages, dryRun)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +182,16 @@ func (c *Client) sendBatch(ctx context.Context, messages []*Message, dryRun bool
 		}
 	}
 
-	return newBatchResponse(resp)
+	response, err = newBatchResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range response.Responses {
+		c.metrics.observeSendResponse(r)
+	}
+
+	return response, nil
 }
 
 // part represents a HTTP request that can be sent embedded in a multipart batch request.
@@ -201,14 +221,15 @@ type fcmResponse struct {
 
 type fcmErrorResponse struct {
 	Error struct {
+		Message string `json:"message"`
 		Details []struct {
 			Type      string `json:"@type"`
 			ErrorCode string `json:"errorCode"`
-		}
+		} `json:"details"`
 	} `json:"error"`
 }
 
-func (c *Client) newBatchRequest(messages []*Message, dryRun bool) (*http.Request, error) {
+func (c *Client) newBatchRequest(ctx context.Context, messages []*Message, dryRun bool) (*http.Request, error) {
 	headers := map[string]string{
 		apiFormatVersionHeader: apiFormatVersion,
 	}
@@ -236,13 +257,13 @@ func (c *Client) newBatchRequest(messages []*Message, dryRun bool) (*http.Reques
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.batchEndpoint, bytes.NewBuffer(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.batchEndpoint, bytes.NewBuffer(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
 
 	// get bearer token
-	token, err := c.tokenProvider.token()
+	token, _, err := c.tokenProvider.Token(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -301,10 +322,13 @@ func newSendResponse(part *multipart.Part) (*SendResponse, error) {
 	}
 
 	if hr.StatusCode != http.StatusOK {
+		fe := parseFCMError(hr.StatusCode, b)
+		if fe == nil {
+			fe = &FCMError{HTTPStatus: hr.StatusCode, Message: string(b)}
+		}
 		return &SendResponse{
-			Success:   false,
-			ErrorCode: hr.StatusCode,
-			ErrorBody: string(b),
+			Success: false,
+			Error:   fe,
 		}, nil
 	}
 
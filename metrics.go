@@ -0,0 +1,102 @@
+package fcm
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// clientMetrics holds the Prometheus collectors WithMetrics registers.
+type clientMetrics struct {
+	sendTotal    *prometheus.CounterVec
+	sendDuration *prometheus.HistogramVec
+	retryTotal   prometheus.Counter
+}
+
+// WithMetrics returns Option to register Prometheus counters and a
+// histogram with reg, tracking send attempts, successes, failures (labeled
+// by FCM error code), retry counts, and end-to-end send latency.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(c *Client) error {
+		m := &clientMetrics{
+			sendTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "fcm",
+				Name:      "send_total",
+				Help:      "Total number of FCM sends, counted once after any retries, labeled by outcome and FCM error code.",
+			}, []string{"outcome", "error_code"}),
+			sendDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: "fcm",
+				Name:      "send_duration_seconds",
+				Help:      "End-to-end latency of FCM send calls, labeled by outcome.",
+				Buckets:   prometheus.DefBuckets,
+			}, []string{"outcome"}),
+			retryTotal: prometheus.NewCounter(prometheus.CounterOpts{
+				Namespace: "fcm",
+				Name:      "retry_total",
+				Help:      "Total number of FCM send attempts that were retried after a transient failure.",
+			}),
+		}
+
+		for _, collector := range []prometheus.Collector{m.sendTotal, m.sendDuration, m.retryTotal} {
+			if err := reg.Register(collector); err != nil {
+				return err
+			}
+		}
+
+		c.metrics = m
+		return nil
+	}
+}
+
+// observeSend records the outcome and end-to-end latency of a single send
+// call, once after all of its retries. It is a no-op if WithMetrics was
+// never configured.
+func (m *clientMetrics) observeSend(start time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	outcome := "success"
+	errorCode := ""
+	if err != nil {
+		outcome = "failure"
+		if fe, ok := err.(*FCMError); ok {
+			errorCode = string(fe.Code)
+		}
+	}
+
+	m.sendTotal.WithLabelValues(outcome, errorCode).Inc()
+	m.sendDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+}
+
+// observeRetry records that a send attempt is being retried. It is a no-op
+// if WithMetrics was never configured.
+func (m *clientMetrics) observeRetry() {
+	if m == nil {
+		return
+	}
+	m.retryTotal.Inc()
+}
+
+// observeSendResponse records the outcome of a single message within a
+// SendAll/SendAllDryRun batch response, so that callers still on the batch
+// endpoint get the same per-error-code breakdown SendEach gets from
+// observeSend. There is no per-message latency to report here, since the
+// batch response doesn't carry individual send timings, so only sendTotal
+// is incremented.
+func (m *clientMetrics) observeSendResponse(r *SendResponse) {
+	if m == nil {
+		return
+	}
+
+	outcome := "success"
+	errorCode := ""
+	if !r.Success {
+		outcome = "failure"
+		if r.Error != nil {
+			errorCode = string(r.Error.Code)
+		}
+	}
+
+	m.sendTotal.WithLabelValues(outcome, errorCode).Inc()
+}
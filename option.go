@@ -23,10 +23,60 @@ func WithEndpoints(endpoint, batchEndpoint string) Option {
 	}
 }
 
-// WithHTTPClient returns Option to configure HTTP Client.
+// WithIIDEndpoint returns Option to configure the Instance ID endpoint used
+// by SubscribeToTopic and UnsubscribeFromTopic, for testing against a local
+// server instead of the production IID API.
+func WithIIDEndpoint(iidEndpoint string) Option {
+	return func(c *Client) error {
+		if iidEndpoint == "" {
+			return errors.New("invalid iidEndpoint")
+		}
+		c.iidEndpoint = iidEndpoint
+		return nil
+	}
+}
+
+// WithHTTPClient returns Option to configure HTTP Client. If a prior
+// WithTransportDefaults or WithMaxConnsPerHost already tuned this Client's
+// own transport and httpClient doesn't specify one of its own, that tuning
+// is carried over instead of silently discarded.
 func WithHTTPClient(httpClient *http.Client) Option {
 	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("http client must not be nil")
+		}
+		if c.ownsTransport && httpClient.Transport == nil {
+			httpClient.Transport = c.client.Transport
+		} else {
+			c.ownsTransport = false
+		}
 		c.client = httpClient
 		return nil
 	}
 }
+
+// WithTokenProvider returns Option to configure the TokenProvider used to
+// authenticate requests, overriding whatever NewClient installed by
+// default. Prefer NewClientWithTokenProvider over NewClient plus this
+// Option, since it skips reading a credentials file altogether.
+func WithTokenProvider(tp TokenProvider) Option {
+	return func(c *Client) error {
+		if tp == nil {
+			return errors.New("token provider must not be nil")
+		}
+		c.tokenProvider = tp
+		return nil
+	}
+}
+
+// WithMaxWorkers returns Option to configure the maximum number of messages
+// that SendEach and friends will have in flight to the FCM server at once.
+func WithMaxWorkers(maxWorkers int) Option {
+	return func(c *Client) error {
+		if maxWorkers <= 0 {
+			return errors.New("maxWorkers must be positive")
+		}
+		c.maxWorkers = maxWorkers
+		return nil
+	}
+}
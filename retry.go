@@ -0,0 +1,161 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures the automatic retry behavior applied to sends that
+// fail with a transient error (429, 500, 502, 503, 504 or a network error).
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is attempted,
+	// including the initial attempt.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. Subsequent retries back
+	// off exponentially from it.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay, before jitter and before any
+	// Retry-After override.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is a ready-made RetryPolicy to pass to WithRetry.
+// WithRetry uses exactly the policy it is given; it does not fill in fields
+// left at zero from DefaultRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    60 * time.Second,
+}
+
+// WithRetry returns an Option that retries transient send failures with
+// exponential backoff, honoring the server's Retry-After header when
+// present. It applies to Client.Send as well as to the per-message fan-out
+// used by SendEach and friends.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		c.retryPolicy = &policy
+		return nil
+	}
+}
+
+// retryableStatusCodes are the HTTP statuses considered transient for the
+// purposes of automatic retries.
+var retryableStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// withRetry runs fn, retrying it according to the client's RetryPolicy if
+// one is configured and fn fails with a transient error.
+func (c *Client) withRetry(ctx context.Context, fn func() error) error {
+	policy := c.retryPolicy
+	if policy == nil {
+		return fn()
+	}
+
+	var err error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		err = fn()
+		if err == nil || attempt == policy.MaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		c.metrics.observeRetry()
+
+		delay := policy.backoff(attempt, retryAfterOf(err))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
+
+// isRetryable reports whether err warrants another attempt. Only FCM/HTTP
+// responses carrying a transient status code, FCM errors that
+// FCMError.IsRetryable reports as transient, and genuine network-level
+// failures (timeouts, connection errors) are retried; permanent failures
+// like a misconfigured TokenProvider or a malformed response body fail
+// immediately instead of burning through the backoff schedule.
+func isRetryable(err error) bool {
+	switch e := err.(type) {
+	case *FCMError:
+		return retryableStatusCodes[e.HTTPStatus] || e.IsRetryable()
+	case HttpError:
+		return retryableStatusCodes[e.StatusCode]
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryAfterOf extracts the Retry-After delay carried by err, if any.
+func retryAfterOf(err error) time.Duration {
+	switch e := err.(type) {
+	case *FCMError:
+		return e.RetryAfter
+	case HttpError:
+		return e.RetryAfter
+	default:
+		return 0
+	}
+}
+
+// backoff computes the delay before the attempt following `attempt`
+// (1-based), honoring a server-provided Retry-After duration when
+// retryAfter is non-zero.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if max := float64(p.MaxDelay); delay > max {
+		delay = max
+	}
+
+	// full jitter: a random delay between 50% and 100% of the computed value
+	jittered := delay * (0.5 + rand.Float64()*0.5)
+	return time.Duration(jittered)
+}
+
+// retryAfterFromResponse parses the Retry-After header in either its
+// seconds or HTTP-date form, returning zero if absent or unparseable.
+func retryAfterFromResponse(header http.Header) time.Duration {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,146 @@
+package fcm
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    60 * time.Second,
+	}
+
+	t.Run("exponential growth within jitter range", func(t *testing.T) {
+		for attempt := 1; attempt <= 4; attempt++ {
+			want := float64(policy.BaseDelay) * float64(uint64(1)<<uint(attempt-1))
+			minDelay := time.Duration(want * 0.5)
+			maxDelay := time.Duration(want)
+
+			for i := 0; i < 20; i++ {
+				d := policy.backoff(attempt, 0)
+				if d < minDelay || d > maxDelay {
+					t.Fatalf("attempt %d: backoff() = %v, want within [%v, %v]", attempt, d, minDelay, maxDelay)
+				}
+			}
+		}
+	})
+
+	t.Run("caps at MaxDelay before jitter", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			d := policy.backoff(20, 0)
+			if d > policy.MaxDelay {
+				t.Fatalf("backoff() = %v, want <= MaxDelay %v", d, policy.MaxDelay)
+			}
+			if d < policy.MaxDelay/2 {
+				t.Fatalf("backoff() = %v, want >= half of MaxDelay %v", d, policy.MaxDelay/2)
+			}
+		}
+	})
+
+	t.Run("retryAfter overrides computed delay", func(t *testing.T) {
+		d := policy.backoff(1, 30*time.Second)
+		if d != 30*time.Second {
+			t.Fatalf("backoff() = %v, want 30s", d)
+		}
+	})
+}
+
+func TestRetryAfterFromResponse(t *testing.T) {
+	t.Run("absent header", func(t *testing.T) {
+		h := http.Header{}
+		if d := retryAfterFromResponse(h); d != 0 {
+			t.Errorf("retryAfterFromResponse() = %v, want 0", d)
+		}
+	})
+
+	t.Run("seconds form", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Retry-After", "120")
+		if d := retryAfterFromResponse(h); d != 120*time.Second {
+			t.Errorf("retryAfterFromResponse() = %v, want 120s", d)
+		}
+	})
+
+	t.Run("HTTP-date form", func(t *testing.T) {
+		future := time.Now().Add(90 * time.Second).UTC()
+		h := http.Header{}
+		h.Set("Retry-After", future.Format(http.TimeFormat))
+
+		d := retryAfterFromResponse(h)
+		if d <= 0 || d > 90*time.Second {
+			t.Errorf("retryAfterFromResponse() = %v, want (0, 90s]", d)
+		}
+	})
+
+	t.Run("past HTTP-date does not return negative duration", func(t *testing.T) {
+		past := time.Now().Add(-90 * time.Second).UTC()
+		h := http.Header{}
+		h.Set("Retry-After", past.Format(http.TimeFormat))
+
+		if d := retryAfterFromResponse(h); d != 0 {
+			t.Errorf("retryAfterFromResponse() = %v, want 0", d)
+		}
+	})
+
+	t.Run("unparseable value", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Retry-After", "not-a-valid-value")
+		if d := retryAfterFromResponse(h); d != 0 {
+			t.Errorf("retryAfterFromResponse() = %v, want 0", d)
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "FCMError with retryable HTTP status",
+			err:  &FCMError{HTTPStatus: http.StatusServiceUnavailable},
+			want: true,
+		},
+		{
+			name: "FCMError with retryable code",
+			err:  &FCMError{HTTPStatus: http.StatusBadRequest, Code: ErrorCodeUnavailable},
+			want: true,
+		},
+		{
+			name: "FCMError with permanent code",
+			err:  &FCMError{HTTPStatus: http.StatusBadRequest, Code: ErrorCodeInvalidArgument},
+			want: false,
+		},
+		{
+			name: "HttpError with retryable status",
+			err:  HttpError{StatusCode: http.StatusTooManyRequests},
+			want: true,
+		},
+		{
+			name: "HttpError with permanent status",
+			err:  HttpError{StatusCode: http.StatusNotFound},
+			want: false,
+		},
+		{
+			name: "arbitrary non-network error",
+			err:  errString("token provider failed"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
@@ -0,0 +1,133 @@
+package fcm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SendEach sends each message in the given array individually via Firebase
+// Cloud Messaging, fanning the requests out across a bounded pool of
+// goroutines (see WithMaxWorkers).
+//
+// Unlike SendAll, SendEach does not use the `/batch` multipart endpoint,
+// which Google has deprecated in favor of plain per-message requests. The
+// messages array may contain up to 500 messages. The responses list
+// obtained from the return value corresponds to the order of the input
+// messages. SendEach only returns an error for setup failures (e.g. an
+// invalid messages array or a cancelled context); per-message failures are
+// folded into the corresponding `SendResponse` instead, so callers can
+// switch between SendAll and SendEach without changing their
+// result-handling code.
+func (c *Client) SendEach(ctx context.Context, messages []*Message) (*MulticastResponse, error) {
+	return c.sendEach(ctx, messages, false)
+}
+
+// SendEachDryRun is the dry run (validation only) counterpart of SendEach.
+//
+// It does not actually deliver any messages to target devices. Instead, it
+// performs all the SDK-level and backend validations on the messages, and
+// emulates the send operation.
+func (c *Client) SendEachDryRun(ctx context.Context, messages []*Message) (*MulticastResponse, error) {
+	return c.sendEach(ctx, messages, true)
+}
+
+// SendEachForMulticast sends the given multicast message to all the FCM
+// registration tokens specified, using the SendEach per-message send path.
+//
+// The tokens array in MulticastMessage may contain up to 500 tokens. The
+// responses list obtained from the return value corresponds to the order of
+// the input tokens.
+func (c *Client) SendEachForMulticast(ctx context.Context, message *MulticastMessage) (*MulticastResponse, error) {
+	messages, err := toMessages(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SendEach(ctx, messages)
+}
+
+// SendEachForMulticastDryRun is the dry run (validation only) counterpart of
+// SendEachForMulticast.
+func (c *Client) SendEachForMulticastDryRun(ctx context.Context, message *MulticastMessage) (*MulticastResponse, error) {
+	messages, err := toMessages(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SendEachDryRun(ctx, messages)
+}
+
+func (c *Client) sendEach(ctx context.Context, messages []*Message, dryRun bool) (*MulticastResponse, error) {
+	if len(messages) == 0 {
+		return nil, errors.New("messages must not be nil or empty")
+	}
+	if len(messages) > maxMessages {
+		return nil, fmt.Errorf("messages must not contain more than %d elements", maxMessages)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ctx, span := c.startSpan(ctx, "fcm.SendEach", dryRun, len(messages))
+	defer span.End()
+
+	responses := make([]*SendResponse, len(messages))
+
+	sem := make(chan struct{}, c.maxWorkers)
+	var wg sync.WaitGroup
+	for i, message := range messages {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, message *Message) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			responses[i] = c.sendOne(ctx, message, dryRun)
+		}(i, message)
+	}
+	wg.Wait()
+
+	successCount := 0
+	for _, r := range responses {
+		if r.Success {
+			successCount++
+		}
+	}
+
+	return &MulticastResponse{
+		Responses:    responses,
+		SuccessCount: successCount,
+		FailureCount: len(responses) - successCount,
+	}, nil
+}
+
+// sendOne sends a single message and always returns a SendResponse, folding
+// any send error into it rather than propagating it, so that a context
+// cancellation is the only way sendEach can fail as a whole.
+func (c *Client) sendOne(ctx context.Context, message *Message, dryRun bool) *SendResponse {
+	if err := message.Validate(); err != nil {
+		return &SendResponse{Error: &FCMError{Message: err.Error()}}
+	}
+
+	data, err := json.Marshal(&SendRequest{Message: message, ValidateOnly: dryRun})
+	if err != nil {
+		return &SendResponse{Error: &FCMError{Message: err.Error()}}
+	}
+
+	messageID, err := c.send(ctx, data, dryRun)
+	if err != nil {
+		switch e := err.(type) {
+		case *FCMError:
+			return &SendResponse{Error: e}
+		case HttpError:
+			return &SendResponse{Error: &FCMError{HTTPStatus: e.StatusCode, Message: e.Error()}}
+		default:
+			return &SendResponse{Error: &FCMError{Message: err.Error()}}
+		}
+	}
+
+	return &SendResponse{Success: true, MessageID: messageID}
+}
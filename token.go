@@ -0,0 +1,125 @@
+package fcm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// fcmMessagingScope is the OAuth2 scope required to call the FCM v1 API.
+const fcmMessagingScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// tokenRefreshSkew is how long before expiry a cached token is considered
+// stale and proactively refreshed, so that high-QPS senders never race the
+// token endpoint under load.
+const tokenRefreshSkew = 60 * time.Second
+
+// TokenProvider supplies the bearer token used to authenticate requests to
+// the FCM API. Implementations are expected to cache and refresh the token
+// as needed. The service account and workload identity providers in this
+// package cache their tokens; a TokenProvider passed to
+// NewClientWithTokenProvider or WithTokenProvider is used as is, with no
+// caching added around it.
+type TokenProvider interface {
+	// Token returns a valid bearer token and its expiry time.
+	Token(ctx context.Context) (string, time.Time, error)
+}
+
+// ServiceAccountFileTokenProvider returns a TokenProvider that authenticates
+// as the service account described by the credentials JSON file at
+// credentialsLocation. This is the TokenProvider NewClient installs by
+// default.
+func ServiceAccountFileTokenProvider(credentialsLocation string) (TokenProvider, error) {
+	raw, err := os.ReadFile(credentialsLocation)
+	if err != nil {
+		return nil, fmt.Errorf("reading credentials file: %w", err)
+	}
+
+	return ServiceAccountJSONTokenProvider(raw)
+}
+
+// ServiceAccountJSONTokenProvider returns a TokenProvider that authenticates
+// as the service account described by the given credentials JSON, without
+// requiring it to live on disk.
+func ServiceAccountJSONTokenProvider(credentialsJSON []byte) (TokenProvider, error) {
+	cfg, err := google.JWTConfigFromJSON(credentialsJSON, fcmMessagingScope)
+	if err != nil {
+		return nil, fmt.Errorf("parsing service account credentials: %w", err)
+	}
+
+	return newCachingTokenProvider(oauth2TokenProvider{cfg.TokenSource(context.Background())}), nil
+}
+
+// WorkloadIdentityTokenProvider returns a TokenProvider that fetches tokens
+// for the instance's attached service account from the GCE/GKE metadata
+// server, for use when running on Google Cloud without a service account
+// key file.
+func WorkloadIdentityTokenProvider() TokenProvider {
+	return newCachingTokenProvider(oauth2TokenProvider{google.ComputeTokenSource("")})
+}
+
+// StaticTokenProvider returns a TokenProvider that always returns the given
+// token and expiry, for use in tests.
+func StaticTokenProvider(token string, expiry time.Time) TokenProvider {
+	return staticTokenProvider{token: token, expiry: expiry}
+}
+
+type staticTokenProvider struct {
+	token  string
+	expiry time.Time
+}
+
+func (p staticTokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	return p.token, p.expiry, nil
+}
+
+// oauth2TokenProvider adapts an oauth2.TokenSource to TokenProvider.
+type oauth2TokenProvider struct {
+	source oauth2.TokenSource
+}
+
+func (p oauth2TokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	tok, err := p.source.Token()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tok.AccessToken, tok.Expiry, nil
+}
+
+// cachingTokenProvider wraps a TokenProvider with an in-memory cache that is
+// proactively refreshed tokenRefreshSkew before it expires, so that
+// high-throughput senders don't stampede the token endpoint on every send.
+type cachingTokenProvider struct {
+	source TokenProvider
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func newCachingTokenProvider(source TokenProvider) *cachingTokenProvider {
+	return &cachingTokenProvider{source: source}
+}
+
+func (p *cachingTokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Add(tokenRefreshSkew).Before(p.expiry) {
+		return p.token, p.expiry, nil
+	}
+
+	token, expiry, err := p.source.Token(ctx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	p.token, p.expiry = token, expiry
+	return token, expiry, nil
+}
@@ -0,0 +1,155 @@
+package fcm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultIIDEndpoint = "https://iid.googleapis.com"
+
+	iidSubscribeAction   = "/iid/v1:batchAdd"
+	iidUnsubscribeAction = "/iid/v1:batchRemove"
+
+	maxTopicManagementTokens = 1000
+)
+
+// TopicManagementResponse represents the result of a SubscribeToTopic or
+// UnsubscribeFromTopic call. The Errors list corresponds to the indices of
+// the tokens array that was passed in.
+type TopicManagementResponse struct {
+	SuccessCount int
+	FailureCount int
+	Errors       []TopicManagementError
+}
+
+// TopicManagementError describes the failure to subscribe or unsubscribe a
+// single token.
+type TopicManagementError struct {
+	Index  int
+	Reason string
+}
+
+// SubscribeToTopic subscribes the given registration tokens to topic, so
+// that messages subsequently sent to the topic are delivered to the devices
+// holding them.
+//
+// The tokens array may contain up to 1000 tokens per call.
+func (c *Client) SubscribeToTopic(ctx context.Context, tokens []string, topic string) (*TopicManagementResponse, error) {
+	return c.manageTopicSubscription(ctx, iidSubscribeAction, tokens, topic)
+}
+
+// UnsubscribeFromTopic unsubscribes the given registration tokens from
+// topic.
+//
+// The tokens array may contain up to 1000 tokens per call.
+func (c *Client) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) (*TopicManagementResponse, error) {
+	return c.manageTopicSubscription(ctx, iidUnsubscribeAction, tokens, topic)
+}
+
+func (c *Client) manageTopicSubscription(ctx context.Context, action string, tokens []string, topic string) (*TopicManagementResponse, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("tokens must not be nil or empty")
+	}
+	if len(tokens) > maxTopicManagementTokens {
+		return nil, fmt.Errorf("tokens must not contain more than %d elements", maxTopicManagementTokens)
+	}
+	if topic == "" {
+		return nil, errors.New("topic must not be empty")
+	}
+
+	ctx, span := c.startSpan(ctx, "fcm."+action, false, len(tokens))
+	defer span.End()
+
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	data, err := json.Marshal(&iidRequest{
+		Topic:              normalizeTopicName(topic),
+		RegistrationTokens: tokens,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.iidEndpoint+action, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// get bearer token
+	token, _, err := c.tokenProvider.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// add headers
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add(apiFormatVersionHeader, apiFormatVersion)
+
+	// execute request
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, HttpError{
+			StatusCode:   resp.StatusCode,
+			ResponseDump: string(respBytes),
+			Err:          fmt.Errorf("%d error: %s", resp.StatusCode, resp.Status),
+		}
+	}
+
+	var iidResp iidResponse
+	if err := json.Unmarshal(respBytes, &iidResp); err != nil {
+		return nil, err
+	}
+
+	tmr := &TopicManagementResponse{}
+	for i, r := range iidResp.Results {
+		if r.Error == "" {
+			tmr.SuccessCount++
+			continue
+		}
+		tmr.FailureCount++
+		tmr.Errors = append(tmr.Errors, TopicManagementError{Index: i, Reason: r.Error})
+	}
+
+	return tmr, nil
+}
+
+// iidRequest is the body of an IID `batchAdd`/`batchRemove` request.
+type iidRequest struct {
+	Topic              string   `json:"to"`
+	RegistrationTokens []string `json:"registration_tokens"`
+}
+
+// iidResponse is the body of an IID `batchAdd`/`batchRemove` response.
+type iidResponse struct {
+	Results []struct {
+		Error string `json:"error,omitempty"`
+	} `json:"results"`
+}
+
+// normalizeTopicName ensures topic carries the "/topics/" prefix the IID API
+// expects, so callers can pass either form.
+func normalizeTopicName(topic string) string {
+	if strings.HasPrefix(topic, "/topics/") {
+		return topic
+	}
+	return "/topics/" + topic
+}
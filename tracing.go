@@ -0,0 +1,59 @@
+package fcm
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies this package's spans to an OpenTelemetry backend.
+const tracerName = "github.com/Valodim/go-fcm"
+
+// WithTracer returns Option to start an OpenTelemetry span around each
+// Send/SendEach/SendAll call and around each individual HTTP round-trip,
+// using the tracer obtained from tp.
+func WithTracer(tp trace.TracerProvider) Option {
+	return func(c *Client) error {
+		c.tracer = tp.Tracer(tracerName)
+		return nil
+	}
+}
+
+// startSpan starts a span named name if a tracer has been configured via
+// WithTracer, tagging it with attributes useful for diagnosing FCM sends:
+// the project ID, whether the call is a dry run, and how many tokens it
+// targets. It returns ctx unchanged, with a no-op span, otherwise.
+func (c *Client) startSpan(ctx context.Context, name string, dryRun bool, tokenCount int) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	return c.tracer.Start(ctx, name, trace.WithAttributes(
+		attribute.String("fcm.project_id", c.projectID),
+		attribute.Bool("fcm.dry_run", dryRun),
+		attribute.Int("fcm.token_count", tokenCount),
+	))
+}
+
+// startRoundTripSpan starts a child span for a single HTTP round-trip, if a
+// tracer has been configured via WithTracer. Unlike startSpan, it carries no
+// message-level attributes: its purpose is to let retried attempts show up
+// as distinct children of the enclosing Send/SendEach/SendAll span, rather
+// than being folded into one flat span that hides how many attempts ran and
+// which of them failed.
+func (c *Client) startRoundTripSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	return c.tracer.Start(ctx, name)
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
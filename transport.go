@@ -0,0 +1,97 @@
+package fcm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultMaxIdleConns        = 200
+	defaultMaxIdleConnsPerHost = 100
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultRequestTimeout      = 10 * time.Second
+)
+
+// WithTransportDefaults returns Option to replace the Client's HTTP
+// transport with one tuned for sending many concurrent, short-lived
+// requests to the FCM server instead of the single occasional request
+// http.DefaultClient is built for: HTTP/2 keep-alive, a warm idle
+// connection pool, and a default per-request timeout. This matters once
+// SendEach fans out one request per message instead of using the `/batch`
+// endpoint, since multiplexing over a warm pool is what gives it
+// throughput parity with batching.
+func WithTransportDefaults() Option {
+	return func(c *Client) error {
+		t := c.transport()
+		t.MaxIdleConns = defaultMaxIdleConns
+		t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+		t.IdleConnTimeout = defaultIdleConnTimeout
+		t.ForceAttemptHTTP2 = true
+		c.requestTimeout = defaultRequestTimeout
+		return nil
+	}
+}
+
+// WithRequestTimeout returns Option to configure the timeout applied to
+// each outgoing HTTP request, overriding the default WithTransportDefaults
+// installs.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return errors.New("request timeout must be positive")
+		}
+		c.requestTimeout = d
+		return nil
+	}
+}
+
+// WithMaxConnsPerHost returns Option to cap the number of simultaneous
+// connections (active plus idle) the Client will open to the FCM server.
+func WithMaxConnsPerHost(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New("maxConnsPerHost must be positive")
+		}
+		c.transport().MaxConnsPerHost = n
+		return nil
+	}
+}
+
+// transport returns the *http.Transport backing the Client's HTTP client,
+// cloning it for this Client's exclusive use the first time a
+// transport-tuning Option needs one, so that tuning never mutates a
+// transport (or http.DefaultClient) a caller might be sharing elsewhere.
+// Later calls reuse the same cloned transport in place.
+func (c *Client) transport() *http.Transport {
+	if c.client == http.DefaultClient {
+		c.client = &http.Client{Timeout: c.client.Timeout}
+	}
+
+	if c.ownsTransport {
+		if t, ok := c.client.Transport.(*http.Transport); ok && t != nil {
+			return t
+		}
+	}
+
+	var t *http.Transport
+	if existing, ok := c.client.Transport.(*http.Transport); ok && existing != nil {
+		t = existing.Clone()
+	} else {
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	c.client.Transport = t
+	c.ownsTransport = true
+	return t
+}
+
+// withRequestTimeout returns a derived context bounded by the Client's
+// configured request timeout, or ctx unchanged if none is set.
+func (c *Client) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.requestTimeout)
+}